@@ -0,0 +1,22 @@
+// Package aguiecho provides an Echo adapter for the AG-UI protocol handler.
+package aguiecho
+
+import (
+	"github.com/labstack/echo/v4"
+	aguigo "github.com/sicko7947/adk-agui-converter"
+)
+
+// Handler returns an echo.HandlerFunc that serves AG-UI protocol requests
+// using the given configuration. Echo's Response() already implements
+// http.ResponseWriter and http.Flusher, so the shared Handler's SSE loop
+// flushes through Echo's own connection as each event is written, and
+// canceling the request context (net/http cancels it when the client
+// disconnects) ends the response stream the same way it would for plain
+// net/http.
+func Handler(cfg aguigo.Config) echo.HandlerFunc {
+	h := aguigo.New(cfg)
+	return func(c echo.Context) error {
+		h.ServeHTTP(c.Response(), c.Request())
+		return nil
+	}
+}
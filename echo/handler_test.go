@@ -0,0 +1,39 @@
+package aguiecho
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/labstack/echo/v4"
+	aguigo "github.com/sicko7947/adk-agui-converter"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEventSource struct {
+	runFunc func(ctx aguigo.HandlerContext, input aguigo.RunAgentInput) <-chan events.Event
+}
+
+func (m *mockEventSource) Run(ctx aguigo.HandlerContext, input aguigo.RunAgentInput) <-chan events.Event {
+	return m.runFunc(ctx, input)
+}
+
+func TestHandler_NDJSON(t *testing.T) {
+	source := &mockEventSource{runFunc: func(aguigo.HandlerContext, aguigo.RunAgentInput) <-chan events.Event {
+		ch := make(chan events.Event, 1)
+		ch <- events.NewRunStartedEvent("t1", "r1")
+		close(ch)
+		return ch
+	}}
+
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"threadId":"t1","runId":"r1"}`))
+	req.Header.Set("Accept", "application/x-ndjson")
+	rr := httptest.NewRecorder()
+	c := e.NewContext(req, rr)
+
+	assert.NoError(t, Handler(aguigo.Config{EventSource: source})(c))
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.Equal(t, 1, strings.Count(rr.Body.String(), "\n"))
+}
@@ -0,0 +1,169 @@
+package aguigo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// mimeMsgpackStream is a length-prefixed MessagePack stream, one frame
+// per event, for high-throughput agent-to-agent transport.
+const mimeMsgpackStream = "application/vnd.agui.msgpack-stream"
+
+// Encoder serializes AG-UI events onto the wire for one content type.
+// WriteEvent is called once per event as the run produces it; Close is
+// called once, after the last event (or immediately, for an empty run)
+// or when the request context is done, to let the encoder write any
+// trailing framing such as closing a JSON array. Close is not called
+// after a WriteEvent error. Encoder instances are constructed fresh per
+// request via the constructor registered in Config.Encoders, since most
+// implementations carry per-connection state.
+type Encoder interface {
+	ContentType() string
+	WriteEvent(ctx context.Context, w io.Writer, evt events.Event) error
+	Close(w io.Writer) error
+}
+
+// defaultEncoders returns the built-in Encoder constructors, keyed by
+// the content type they produce.
+func defaultEncoders() map[string]func() Encoder {
+	return map[string]func() Encoder{
+		mimeJSON:          func() Encoder { return &jsonArrayEncoder{} },
+		mimeNDJSON:        func() Encoder { return ndjsonEncoder{} },
+		mimeMsgpackStream: func() Encoder { return msgpackStreamEncoder{} },
+	}
+}
+
+// NegotiatedContentType returns the Content-Type ServeHTTP will use for a
+// request with the given Accept header: the ContentType of whichever
+// Encoder selectEncoder would pick, or text/event-stream when nothing
+// matches and the resumable SSE path applies instead. Adapters whose
+// underlying framework serializes response headers before the Handler
+// gets to write its body (so setting Content-Type from inside the
+// response it writes to has no effect) can call this to decide the
+// header value up front instead of re-implementing content negotiation.
+func (h *Handler) NegotiatedContentType(accept string) string {
+	if enc, ok := h.selectEncoder(accept); ok {
+		return enc.ContentType()
+	}
+	return mimeSSE
+}
+
+// selectEncoder parses the Accept header using RFC 7231 quality values
+// and picks the registered encoder with the highest-quality match. It's
+// the one content-negotiation algorithm for ServeHTTP's response
+// encoding; text/event-stream and "*/*" are left unmatched since those
+// are served by the resumable SSE path rather than an Encoder.
+func (h *Handler) selectEncoder(accept string) (Encoder, bool) {
+	if accept == "" {
+		return nil, false
+	}
+
+	var best func() Encoder
+	bestQ := -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mt, q := parseMediaType(part)
+		if mt == mimeSSE || mt == "*/*" {
+			continue
+		}
+		ctor, ok := h.encoders[mt]
+		if !ok || q <= bestQ {
+			continue
+		}
+		best, bestQ = ctor, q
+	}
+	if best == nil {
+		return nil, false
+	}
+	return best(), true
+}
+
+// jsonArrayEncoder buffers events into a single JSON array document.
+type jsonArrayEncoder struct {
+	wrote bool
+}
+
+func (e *jsonArrayEncoder) ContentType() string { return mimeJSON }
+
+func (e *jsonArrayEncoder) WriteEvent(_ context.Context, w io.Writer, evt events.Event) error {
+	data, err := evt.ToJSON()
+	if err != nil {
+		return err
+	}
+	sep := ","
+	if !e.wrote {
+		sep = "["
+	}
+	e.wrote = true
+
+	if _, err := io.WriteString(w, sep); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (e *jsonArrayEncoder) Close(w io.Writer) error {
+	if !e.wrote {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// ndjsonEncoder streams one JSON object per line.
+type ndjsonEncoder struct{}
+
+func (ndjsonEncoder) ContentType() string { return mimeNDJSON }
+
+func (ndjsonEncoder) WriteEvent(_ context.Context, w io.Writer, evt events.Event) error {
+	data, err := evt.ToJSON()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	_, err = w.Write([]byte("\n"))
+	return err
+}
+
+func (ndjsonEncoder) Close(io.Writer) error { return nil }
+
+// msgpackStreamEncoder writes each event as a big-endian uint32 length
+// prefix followed by its MessagePack encoding. It's handed the raw
+// events.Event, rather than going through evt.ToJSON(), so typed fields
+// -- e.g. binary tool-output payloads -- are encoded directly instead of
+// round-tripping through base64-in-JSON. events.Event structs only carry
+// json tags, so the encoder is told to key off those instead of its
+// default msgpack tag, keeping the wire field names identical to the
+// SSE/JSON/NDJSON encoders.
+type msgpackStreamEncoder struct{}
+
+func (msgpackStreamEncoder) ContentType() string { return mimeMsgpackStream }
+
+func (msgpackStreamEncoder) WriteEvent(_ context.Context, w io.Writer, evt events.Event) error {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(evt); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func (msgpackStreamEncoder) Close(io.Writer) error { return nil }
@@ -0,0 +1,118 @@
+package aguigo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestJSONArrayEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := &jsonArrayEncoder{}
+
+	assert.Equal(t, mimeJSON, enc.ContentType())
+	assert.NoError(t, enc.WriteEvent(context.Background(), &buf, events.NewRunStartedEvent("t1", "r1")))
+	assert.NoError(t, enc.WriteEvent(context.Background(), &buf, events.NewRunFinishedEvent("t1", "r1")))
+	assert.NoError(t, enc.Close(&buf))
+
+	assert.Equal(t, byte('['), buf.Bytes()[0])
+	assert.Equal(t, byte(']'), buf.Bytes()[buf.Len()-1])
+
+	var decoded []json.RawMessage
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded, 2)
+}
+
+func TestJSONArrayEncoder_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	enc := &jsonArrayEncoder{}
+
+	assert.NoError(t, enc.Close(&buf))
+	assert.Equal(t, "[]", buf.String())
+}
+
+func TestNDJSONEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := ndjsonEncoder{}
+
+	assert.Equal(t, mimeNDJSON, enc.ContentType())
+	assert.NoError(t, enc.WriteEvent(context.Background(), &buf, events.NewRunStartedEvent("t1", "r1")))
+	assert.NoError(t, enc.WriteEvent(context.Background(), &buf, events.NewRunFinishedEvent("t1", "r1")))
+
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestMsgpackStreamEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc := msgpackStreamEncoder{}
+
+	assert.Equal(t, mimeMsgpackStream, enc.ContentType())
+	assert.NoError(t, enc.WriteEvent(context.Background(), &buf, events.NewRunStartedEvent("t1", "r1")))
+
+	length := binary.BigEndian.Uint32(buf.Bytes()[:4])
+	assert.EqualValues(t, buf.Len()-4, length)
+
+	var decoded map[string]any
+	assert.NoError(t, msgpack.Unmarshal(buf.Bytes()[4:], &decoded))
+	assert.Equal(t, "t1", decoded["threadId"])
+	assert.Equal(t, "r1", decoded["runId"])
+}
+
+func TestHandler_SelectEncoder(t *testing.T) {
+	handler := New(Config{EventSource: &MockEventSource{}})
+
+	cases := []struct {
+		name        string
+		accept      string
+		wantMatch   bool
+		wantContent string
+	}{
+		{"empty accept defers to SSE", "", false, ""},
+		{"SSE accept defers to SSE", "text/event-stream", false, ""},
+		{"wildcard defers to SSE", "*/*", false, ""},
+		{"JSON accept", "application/json", true, mimeJSON},
+		{"NDJSON accept", "application/x-ndjson", true, mimeNDJSON},
+		{"msgpack accept", "application/vnd.agui.msgpack-stream", true, mimeMsgpackStream},
+		{"quality values pick the highest", "application/json;q=0.5, application/x-ndjson;q=0.9", true, mimeNDJSON},
+		{"unregistered type defers to SSE", "application/xml", false, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			enc, ok := handler.selectEncoder(tc.accept)
+			assert.Equal(t, tc.wantMatch, ok)
+			if ok {
+				assert.Equal(t, tc.wantContent, enc.ContentType())
+			}
+		})
+	}
+}
+
+func TestHandler_SelectEncoder_CustomEncoder(t *testing.T) {
+	const mimeCSV = "text/csv"
+	handler := New(Config{
+		EventSource: &MockEventSource{},
+		Encoders: map[string]func() Encoder{
+			mimeCSV: func() Encoder { return csvStubEncoder{} },
+		},
+	})
+
+	enc, ok := handler.selectEncoder(mimeCSV)
+	assert.True(t, ok)
+	assert.Equal(t, mimeCSV, enc.ContentType())
+}
+
+type csvStubEncoder struct{}
+
+func (csvStubEncoder) ContentType() string { return "text/csv" }
+func (csvStubEncoder) WriteEvent(ctx context.Context, w io.Writer, evt events.Event) error {
+	return nil
+}
+func (csvStubEncoder) Close(w io.Writer) error { return nil }
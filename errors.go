@@ -0,0 +1,85 @@
+package aguigo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// problemJSONContentType is the media type used for ErrorResponse bodies,
+// per RFC 7807 ("Problem Details for HTTP APIs").
+const problemJSONContentType = "application/problem+json"
+
+// ErrorResponse is an RFC 7807 problem-details envelope returned for
+// the request-level error paths in Handler.ServeHTTP and for mid-stream
+// SSE write failures. A WriteEvent failure on a non-SSE Encoder response
+// is only logged; no ErrorResponse is written for it.
+type ErrorResponse struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	ThreadID string `json:"threadId,omitempty"`
+	RunID    string `json:"runId,omitempty"`
+}
+
+// ErrorMapper translates an internal error into a stable, protocol-level
+// ErrorResponse. Apps set Config.ErrorMapper to control what detail (if
+// any) reaches clients; the zero value of the returned ErrorResponse's
+// Status field falls back to the HTTP status the handler was already
+// going to use for that error path.
+type ErrorMapper func(error) ErrorResponse
+
+// JSONResponse writes v as a JSON response body with the given status
+// code and content type.
+func JSONResponse(w http.ResponseWriter, status int, contentType string, v any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// mapError builds the ErrorResponse for err, preferring the configured
+// ErrorMapper and falling back to a generic mapping based on status.
+func (h *Handler) mapError(err error, status int) ErrorResponse {
+	if h.errorMapper != nil {
+		resp := h.errorMapper(err)
+		if resp.Status == 0 {
+			resp.Status = status
+		}
+		return resp
+	}
+	return ErrorResponse{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+}
+
+// writeError writes err as a problem+json response, stamping in the
+// thread/run IDs from hctx when they're known.
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error, hctx HandlerContext) {
+	resp := h.mapError(err, status)
+	resp.ThreadID = hctx.ThreadID
+	resp.RunID = hctx.RunID
+	JSONResponse(w, resp.Status, problemJSONContentType, resp)
+}
+
+// writeSSEError sends a terminal `event: error` frame carrying an
+// ErrorResponse body, for failures that happen mid-stream (after
+// headers and possibly other events have already been written, so a
+// plain HTTP error response is no longer possible).
+func (h *Handler) writeSSEError(w http.ResponseWriter, err error, hctx HandlerContext) {
+	resp := h.mapError(err, http.StatusInternalServerError)
+	resp.ThreadID = hctx.ThreadID
+	resp.RunID = hctx.RunID
+
+	data, marshalErr := json.Marshal(resp)
+	if marshalErr != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
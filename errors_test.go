@@ -0,0 +1,65 @@
+package aguigo
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ErrorResponses(t *testing.T) {
+	handler := New(Config{EventSource: &MockEventSource{}})
+
+	t.Run("Method not allowed returns problem+json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `"status":405`)
+	})
+
+	t.Run("Invalid JSON returns problem+json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not json"))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), "invalid JSON")
+	})
+}
+
+func TestHandler_ErrorMapper(t *testing.T) {
+	handler := New(Config{
+		EventSource: &MockEventSource{},
+		ErrorMapper: func(err error) ErrorResponse {
+			return ErrorResponse{Title: "mapped", Detail: "redacted"}
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("not json")))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"title":"mapped"`)
+	assert.Contains(t, rr.Body.String(), `"detail":"redacted"`)
+}
+
+func TestHandler_writeSSEError(t *testing.T) {
+	handler := New(Config{EventSource: &MockEventSource{}})
+	rr := httptest.NewRecorder()
+
+	handler.writeSSEError(rr, errors.New("boom"), HandlerContext{ThreadID: "t1", RunID: "r1"})
+
+	body := rr.Body.String()
+	assert.True(t, strings.HasPrefix(body, "event: error\ndata: "))
+	assert.Contains(t, body, `"detail":"boom"`)
+	assert.Contains(t, body, `"threadId":"t1"`)
+}
@@ -0,0 +1,83 @@
+// Package aguifiber provides a Fiber adapter for the AG-UI protocol handler.
+package aguifiber
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	aguigo "github.com/sicko7947/adk-agui-converter"
+)
+
+// Handler returns a fiber.Handler that serves AG-UI protocol requests using
+// the given configuration.
+//
+// Fiber sits on fasthttp rather than net/http, so every response is
+// driven through a SetBodyStreamWriter callback: fasthttp serializes the
+// response headers before it starts pulling body bytes, so headers set
+// from inside that callback (via streamWriter.Header()) never reach the
+// client. The Content-Type is therefore negotiated up front, the same
+// way the shared Handler would negotiate it for this Accept header, and
+// set on the fiber.Ctx before streaming starts.
+func Handler(cfg aguigo.Config) fiber.Handler {
+	h := aguigo.New(cfg)
+	return func(c *fiber.Ctx) error {
+		req, err := toHTTPRequest(c)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, err.Error())
+		}
+
+		contentType := h.NegotiatedContentType(req.Header.Get(fiber.HeaderAccept))
+		c.Set(fiber.HeaderContentType, contentType)
+		if contentType == "text/event-stream" {
+			c.Set(fiber.HeaderCacheControl, "no-cache")
+			c.Set(fiber.HeaderConnection, "keep-alive")
+			c.Set("X-Accel-Buffering", "no")
+		}
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer w.Flush()
+			h.ServeHTTP(&streamWriter{header: make(http.Header), w: w}, req)
+		})
+		return nil
+	}
+}
+
+// toHTTPRequest translates a fasthttp-backed fiber.Ctx into a standard
+// *http.Request so it can be handed to the shared, framework-agnostic
+// Handler unchanged.
+func toHTTPRequest(c *fiber.Ctx) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(c.Context(), c.Method(), c.OriginalURL(), bytes.NewReader(c.Body()))
+	if err != nil {
+		return nil, err
+	}
+	c.Request().Header.VisitAll(func(k, v []byte) {
+		req.Header.Add(string(k), string(v))
+	})
+	req.RemoteAddr = c.IP()
+	return req, nil
+}
+
+// streamWriter adapts a fasthttp body stream into an http.ResponseWriter
+// so the shared Handler can write its response body -- SSE frames, a
+// JSON array, NDJSON lines, or a msgpack stream -- directly to the wire.
+// Header values set through it are not forwarded to fasthttp: by the
+// time the stream writer runs, fasthttp has already serialized the
+// response headers set on the fiber.Ctx above, so only those apply.
+type streamWriter struct {
+	header      http.Header
+	w           *bufio.Writer
+	wroteHeader bool
+}
+
+func (s *streamWriter) Header() http.Header { return s.header }
+
+func (s *streamWriter) WriteHeader(int) { s.wroteHeader = true }
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.wroteHeader = true
+	return s.w.Write(p)
+}
+
+func (s *streamWriter) Flush() { s.w.Flush() }
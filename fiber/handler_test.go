@@ -0,0 +1,92 @@
+package aguifiber
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/gofiber/fiber/v2"
+	aguigo "github.com/sicko7947/adk-agui-converter"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEventSource struct {
+	runFunc func(ctx aguigo.HandlerContext, input aguigo.RunAgentInput) <-chan events.Event
+}
+
+func (m *mockEventSource) Run(ctx aguigo.HandlerContext, input aguigo.RunAgentInput) <-chan events.Event {
+	return m.runFunc(ctx, input)
+}
+
+func newTestApp(source aguigo.EventSource) *fiber.App {
+	app := fiber.New()
+	app.Post("/", Handler(aguigo.Config{EventSource: source}))
+	return app
+}
+
+func twoEventSource() *mockEventSource {
+	return &mockEventSource{runFunc: func(aguigo.HandlerContext, aguigo.RunAgentInput) <-chan events.Event {
+		ch := make(chan events.Event, 2)
+		ch <- events.NewRunStartedEvent("t1", "r1")
+		ch <- events.NewRunFinishedEvent("t1", "r1")
+		close(ch)
+		return ch
+	}}
+}
+
+func TestHandler_NDJSONGetsNDJSONContentType(t *testing.T) {
+	app := newTestApp(twoEventSource())
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"threadId":"t1","runId":"r1"}`))
+	req.Header.Set("Accept", "application/x-ndjson")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(body), "\n"))
+}
+
+func TestHandler_MsgpackStreamGetsMsgpackContentType(t *testing.T) {
+	app := newTestApp(twoEventSource())
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"threadId":"t1","runId":"r1"}`))
+	req.Header.Set("Accept", "application/vnd.agui.msgpack-stream")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/vnd.agui.msgpack-stream", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	// One length-prefixed frame per event: two frames that together
+	// account for every byte of the body.
+	assert.Greater(t, len(body), 4)
+	length := binary.BigEndian.Uint32(body[:4])
+	assert.Greater(t, len(body), 4+int(length)+4)
+	rest := body[4+int(length):]
+	second := binary.BigEndian.Uint32(rest[:4])
+	assert.EqualValues(t, len(rest)-4, int(second))
+}
+
+func TestHandler_SSEIsStillTheDefault(t *testing.T) {
+	app := newTestApp(twoEventSource())
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"threadId":"t1","runId":"r1"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(body), "data:"))
+}
@@ -0,0 +1,19 @@
+// Package aguigin provides a Gin adapter for the AG-UI protocol handler.
+package aguigin
+
+import (
+	"github.com/gin-gonic/gin"
+	aguigo "github.com/sicko7947/adk-agui-converter"
+)
+
+// Handler returns a gin.HandlerFunc that serves AG-UI protocol requests
+// using the given configuration. gin.Context.Writer implements
+// http.Flusher, so the shared Handler flushes SSE events through it
+// directly; routing through c.Stream isn't necessary since the Handler
+// already owns the write-and-flush loop for every event.
+func Handler(cfg aguigo.Config) gin.HandlerFunc {
+	h := aguigo.New(cfg)
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,42 @@
+package aguigin
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/gin-gonic/gin"
+	aguigo "github.com/sicko7947/adk-agui-converter"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEventSource struct {
+	runFunc func(ctx aguigo.HandlerContext, input aguigo.RunAgentInput) <-chan events.Event
+}
+
+func (m *mockEventSource) Run(ctx aguigo.HandlerContext, input aguigo.RunAgentInput) <-chan events.Event {
+	return m.runFunc(ctx, input)
+}
+
+func TestHandler_NDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	source := &mockEventSource{runFunc: func(aguigo.HandlerContext, aguigo.RunAgentInput) <-chan events.Event {
+		ch := make(chan events.Event, 1)
+		ch <- events.NewRunStartedEvent("t1", "r1")
+		close(ch)
+		return ch
+	}}
+
+	engine := gin.New()
+	engine.POST("/", Handler(aguigo.Config{EventSource: source}))
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"threadId":"t1","runId":"r1"}`))
+	req.Header.Set("Accept", "application/x-ndjson")
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.Equal(t, 1, strings.Count(rr.Body.String(), "\n"))
+}
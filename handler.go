@@ -8,6 +8,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
 	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/encoding/sse"
@@ -98,12 +100,26 @@ type EventSource interface {
 	Run(ctx HandlerContext, input RunAgentInput) <-chan events.Event
 }
 
+// sseEventWriter is satisfied by sse.NewSSEWriter()'s return value. It's
+// declared locally, rather than referencing the sse package's concrete
+// type, purely so streamRun's helpers can be unit tested with a fake.
+type sseEventWriter interface {
+	WriteEvent(ctx context.Context, w io.Writer, evt events.Event) error
+}
+
 // HandlerContext provides context for the agent run
 type HandlerContext struct {
 	ThreadID string
 	RunID    string
 	UserID   string
 	Request  *http.Request
+
+	// Logger is the request-scoped Logger to use for this run. It's the
+	// handler's configured Logger by default, or, when JSONLoggerMiddleware
+	// is in front of the Handler, that middleware's per-request Logger
+	// enriched with threadId/runId so EventSource.Run implementations log
+	// with the same correlation fields as the access log.
+	Logger Logger
 }
 
 // Config configures the handler
@@ -111,6 +127,26 @@ type Config struct {
 	EventSource EventSource
 	AppName     string
 	Logger      Logger
+
+	// ErrorMapper translates internal errors into stable, protocol-level
+	// ErrorResponse values. If nil, errors are mapped to a generic
+	// ErrorResponse carrying err.Error() as the detail.
+	ErrorMapper ErrorMapper
+
+	// ReplayStore buffers recently emitted events per run so a
+	// reconnecting SSE client (one that sends Last-Event-ID) can replay
+	// what it missed. If nil, an in-memory store sized for
+	// defaultReplayMaxRuns runs is used.
+	ReplayStore ReplayStore
+
+	// Encoders registers additional Encoder constructors, keyed by the
+	// content type they produce, or overrides a built-in one under the
+	// same key. The built-in encoders for application/json,
+	// application/x-ndjson, and application/vnd.agui.msgpack-stream are
+	// always available unless overridden here. text/event-stream is
+	// handled separately by the resumable SSE path and isn't registered
+	// through Encoders.
+	Encoders map[string]func() Encoder
 }
 
 // Logger interface for logging
@@ -127,6 +163,12 @@ type Handler struct {
 	eventSource EventSource
 	appName     string
 	logger      Logger
+	errorMapper ErrorMapper
+	replayStore ReplayStore
+	encoders    map[string]func() Encoder
+
+	runsMu sync.Mutex
+	runs   map[string]*run // in-flight runs, keyed by RunID
 }
 
 // New creates a new AG-UI handler
@@ -136,10 +178,24 @@ func New(config Config) *Handler {
 		logger = defaultLogger{}
 	}
 
+	replayStore := config.ReplayStore
+	if replayStore == nil {
+		replayStore = NewMemoryReplayStore(defaultReplayMaxRuns, defaultReplayBufferSize)
+	}
+
+	encoders := defaultEncoders()
+	for contentType, ctor := range config.Encoders {
+		encoders[contentType] = ctor
+	}
+
 	return &Handler{
 		eventSource: config.EventSource,
 		appName:     config.AppName,
 		logger:      logger,
+		errorMapper: config.ErrorMapper,
+		replayStore: replayStore,
+		encoders:    encoders,
+		runs:        make(map[string]*run),
 	}
 }
 
@@ -147,26 +203,32 @@ func New(config Config) *Handler {
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.logger.Printf("[AG-UI] Received %s request from %s", r.Method, r.RemoteAddr)
 
-	if r.Method == http.MethodOptions {
+	switch r.Method {
+	case http.MethodOptions:
 		h.handleCORS(w)
 		return
-	}
-
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	case http.MethodGet:
+		// A GET carrying Last-Event-ID is a standard EventSource
+		// reconnect: resume an existing run rather than starting one.
+		h.handleReconnect(w, r)
+		return
+	case http.MethodPost:
+		// Starts a new run; handled below.
+	default:
+		h.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method), HandlerContext{})
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to read request body: %w", err), HandlerContext{})
 		return
 	}
 	defer r.Body.Close()
 
 	var input RunAgentInput
 	if err := json.Unmarshal(body, &input); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid JSON: %w", err), HandlerContext{})
 		return
 	}
 
@@ -177,19 +239,57 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		input.RunID = events.GenerateRunID()
 	}
 
+	// Exposed so JSONLoggerMiddleware (or any other wrapping middleware)
+	// can correlate its access log entry with this run after the fact.
+	w.Header().Set("X-Thread-ID", input.ThreadID)
+	w.Header().Set("X-Run-ID", input.RunID)
+
+	requestLogger := loggerFromContext(r.Context())
+	if requestLogger == nil {
+		requestLogger = h.logger
+	}
+
 	ctx := HandlerContext{
 		ThreadID: input.ThreadID,
 		RunID:    input.RunID,
 		UserID:   r.Header.Get("X-User-ID"),
 		Request:  r,
+		Logger:   WithFields(requestLogger, map[string]string{"threadId": input.ThreadID, "runId": input.RunID}),
 	}
 
-	accept := r.Header.Get("Accept")
-	if accept == "" || accept == "text/event-stream" || accept == "*/*" {
-		h.handleSSE(w, r.Context(), ctx, input)
-	} else {
-		h.handleJSON(w, r.Context(), ctx, input)
+	if enc, ok := h.selectEncoder(r.Header.Get("Accept")); ok {
+		h.handleEncoded(w, r.Context(), ctx, input, enc)
+		return
+	}
+	h.handleSSE(w, r, ctx, input)
+}
+
+// handleReconnect serves a GET reconnect request: the client identifies
+// the run it wants to resume via the runId query parameter and tells us
+// how far it got via the Last-Event-ID header.
+func (h *Handler) handleReconnect(w http.ResponseWriter, r *http.Request) {
+	runID := r.URL.Query().Get("runId")
+	if runID == "" {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("runId query parameter is required to resume a run"), HandlerContext{})
+		return
+	}
+
+	hctx := HandlerContext{
+		ThreadID: r.URL.Query().Get("threadId"),
+		RunID:    runID,
+		UserID:   r.Header.Get("X-User-ID"),
+		Request:  r,
 	}
+
+	var lastID int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		if parsed, err := strconv.ParseInt(v, 10, 64); err == nil {
+			lastID = parsed
+		}
+	}
+
+	h.setSSEHeaders(w)
+	h.streamRun(w, r.Context(), hctx, h.lookupRun(runID), lastID)
 }
 
 func (h *Handler) handleCORS(w http.ResponseWriter) {
@@ -199,46 +299,154 @@ func (h *Handler) handleCORS(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *Handler) handleSSE(w http.ResponseWriter, ctx context.Context, hctx HandlerContext, input RunAgentInput) {
+// handleSSE starts a new run and streams it as SSE to this connection.
+// The run itself is handed off to a background goroutine (see startRun)
+// so it keeps producing events, and keeps feeding the ReplayStore, even
+// if this connection drops before the run finishes; a reconnect can
+// then resume it via handleReconnect.
+func (h *Handler) handleSSE(w http.ResponseWriter, r *http.Request, hctx HandlerContext, input RunAgentInput) {
+	h.setSSEHeaders(w)
+	rn := h.startRun(hctx, input)
+	h.streamRun(w, r.Context(), hctx, rn, 0)
+}
+
+func (h *Handler) setSSEHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("X-Accel-Buffering", "no")
+}
 
+// streamRun first replays, from the ReplayStore, whatever was appended
+// to hctx.RunID after lastID, then, if rn is non-nil, attaches to its
+// live events until the run finishes or ctx is done. rn is nil when the
+// run is not in flight on this server (never existed, or already
+// completed), in which case only the buffered backlog, if any, is
+// replayed.
+//
+// Subscribing before consulting the ReplayStore (rather than after) is
+// what makes this race-free: any event the run produces between the two
+// is both captured by the replay read below and delivered again on sub,
+// so lastSent is used to drop that duplicate rather than to detect a gap.
+func (h *Handler) streamRun(w http.ResponseWriter, ctx context.Context, hctx HandlerContext, rn *run, lastID int64) {
 	writer := sse.NewSSEWriter()
-	eventsChan := h.eventSource.Run(hctx, input)
 
-	for evt := range eventsChan {
-		if err := writer.WriteEvent(ctx, w, evt); err != nil {
-			h.logger.Printf("[AG-UI] Failed to send event: %v", err)
+	var sub chan seqEvent
+	if rn != nil {
+		var unsubscribe func()
+		sub, unsubscribe = rn.subscribe()
+		defer unsubscribe()
+	}
+
+	lastSent := lastID
+	for _, evt := range h.replayStore.Since(hctx.RunID, lastID) {
+		lastSent++
+		if !h.writeSSEFrame(ctx, w, writer, lastSent, evt, hctx) {
 			return
 		}
-		if f, ok := w.(http.Flusher); ok {
-			f.Flush()
+	}
+
+	if rn == nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case se, ok := <-sub:
+			if !ok {
+				return
+			}
+			if !h.deliver(ctx, w, writer, se, &lastSent, hctx) {
+				return
+			}
+		case <-rn.done:
+			h.drainSub(ctx, w, writer, sub, &lastSent, hctx)
+			return
 		}
 	}
 }
 
-func (h *Handler) handleJSON(w http.ResponseWriter, ctx context.Context, hctx HandlerContext, input RunAgentInput) {
-	var allEvents []events.Event
-
-	eventsChan := h.eventSource.Run(hctx, input)
-	for evt := range eventsChan {
-		allEvents = append(allEvents, evt)
+// deliver writes se unless its id was already sent (a duplicate from the
+// ReplayStore/live-channel race described on streamRun), advancing
+// *lastSent on success.
+func (h *Handler) deliver(ctx context.Context, w http.ResponseWriter, writer sseEventWriter, se seqEvent, lastSent *int64, hctx HandlerContext) bool {
+	if se.id <= *lastSent {
+		return true
+	}
+	if !h.writeSSEFrame(ctx, w, writer, se.id, se.evt, hctx) {
+		return false
 	}
+	*lastSent = se.id
+	return true
+}
 
-	var jsonEvents []json.RawMessage
-	for _, evt := range allEvents {
-		data, err := evt.ToJSON()
-		if err != nil {
-			continue
+// drainSub flushes whatever is already buffered in sub once the run has
+// finished, so events published just before rn.done closed aren't lost
+// to the race between the two select cases becoming ready together.
+func (h *Handler) drainSub(ctx context.Context, w http.ResponseWriter, writer sseEventWriter, sub <-chan seqEvent, lastSent *int64, hctx HandlerContext) {
+	for {
+		select {
+		case se := <-sub:
+			if !h.deliver(ctx, w, writer, se, lastSent, hctx) {
+				return
+			}
+		default:
+			return
 		}
-		jsonEvents = append(jsonEvents, data)
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(jsonEvents)
+// writeSSEFrame assigns evt the given replay id, writes it to w, and
+// flushes. It returns false (after sending a terminal error frame) if
+// the write failed, signaling the caller to stop streaming.
+func (h *Handler) writeSSEFrame(ctx context.Context, w http.ResponseWriter, writer sseEventWriter, id int64, evt events.Event, hctx HandlerContext) bool {
+	fmt.Fprintf(w, "id: %d\n", id)
+	if err := writer.WriteEvent(ctx, w, evt); err != nil {
+		h.logger.Printf("[AG-UI] Failed to send event: %v", err)
+		h.writeSSEError(w, err, hctx)
+		return false
+	}
+	if ec, ok := w.(eventCounter); ok {
+		ec.CountEvent()
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return true
+}
+
+// handleEncoded serves the run through enc: a non-resumable, non-SSE
+// transport (JSON array, NDJSON, msgpack stream, or a custom one
+// registered via Config.Encoders) chosen by selectEncoder.
+func (h *Handler) handleEncoded(w http.ResponseWriter, ctx context.Context, hctx HandlerContext, input RunAgentInput, enc Encoder) {
+	w.Header().Set("Content-Type", enc.ContentType())
+	eventsChan := h.eventSource.Run(hctx, input)
+
+	for {
+		select {
+		case <-ctx.Done():
+			enc.Close(w)
+			return
+		case evt, ok := <-eventsChan:
+			if !ok {
+				enc.Close(w)
+				return
+			}
+			if err := enc.WriteEvent(ctx, w, evt); err != nil {
+				h.logger.Printf("[AG-UI] Failed to encode event: %v", err)
+				return
+			}
+			if ec, ok := w.(eventCounter); ok {
+				ec.CountEvent()
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+	}
 }
 
 // HealthHandler returns a simple health check endpoint
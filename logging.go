@@ -0,0 +1,214 @@
+package aguigo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// accessLogEntry is the one JSON line emitted per request by
+// JSONLoggerMiddleware.
+type accessLogEntry struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	RemoteAddr string `json:"remoteAddr"`
+	UserID     string `json:"userId,omitempty"`
+	ThreadID   string `json:"threadId,omitempty"`
+	RunID      string `json:"runId,omitempty"`
+	BytesIn    int64  `json:"bytesIn"`
+	BytesOut   int64  `json:"bytesOut"`
+	EventCount int    `json:"eventCount,omitempty"`
+}
+
+// LoggerConfig configures JSONLoggerMiddleware.
+type LoggerConfig struct {
+	// Logger receives the one-line JSON access log entry per request,
+	// and, augmented with per-request fields, is threaded into
+	// HandlerContext.Logger for EventSource.Run implementations to log
+	// through with the same correlation fields. Defaults to StdLogger{}.
+	Logger Logger
+
+	// Sample, if set, is consulted per request; requests for which it
+	// returns false are served normally but produce no access log entry.
+	Sample func(r *http.Request) bool
+
+	// Redact, if set, is applied to every message logged through the
+	// Logger threaded into HandlerContext (but not to the structured
+	// access log entry's own fields), letting apps strip or mask
+	// message content before it reaches Logger.
+	Redact func(msg string) string
+}
+
+// JSONLoggerMiddleware returns middleware that emits one structured
+// JSON access log line per request and threads a correlated Logger into
+// HandlerContext via the request context, so the eventual
+// EventSource.Run can log with the same method/path/remoteAddr/userId
+// fields (and, once the Handler parses them, threadId/runId).
+func JSONLoggerMiddleware(cfg LoggerConfig) func(http.Handler) http.Handler {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = StdLogger{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			skip := cfg.Sample != nil && !cfg.Sample(r)
+
+			start := time.Now()
+			rl := &responseLogger{ResponseWriter: w}
+
+			reqLogger := Logger(logger)
+			if cfg.Redact != nil {
+				reqLogger = &redactingLogger{base: reqLogger, redact: cfg.Redact}
+			}
+			reqLogger = WithFields(reqLogger, map[string]string{
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"remoteAddr": r.RemoteAddr,
+				"userId":     r.Header.Get("X-User-ID"),
+			})
+
+			next.ServeHTTP(rl, r.WithContext(contextWithLogger(r.Context(), reqLogger)))
+
+			if skip {
+				return
+			}
+
+			entry := accessLogEntry{
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Status:     rl.status(),
+				DurationMs: time.Since(start).Milliseconds(),
+				RemoteAddr: r.RemoteAddr,
+				UserID:     r.Header.Get("X-User-ID"),
+				ThreadID:   rl.Header().Get("X-Thread-ID"),
+				RunID:      rl.Header().Get("X-Run-ID"),
+				BytesIn:    r.ContentLength,
+				BytesOut:   rl.bytesOut,
+				EventCount: rl.eventCount,
+			}
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return
+			}
+			logger.Printf("%s", data)
+		})
+	}
+}
+
+// eventCounter is satisfied by an http.ResponseWriter that wants to know
+// when writeSSEFrame/handleEncoded have written one complete event, such
+// as responseLogger.
+type eventCounter interface {
+	CountEvent()
+}
+
+// responseLogger wraps an http.ResponseWriter to sniff the status code
+// and count bytes/events written, while still passing through
+// http.Flusher so streamed (SSE/NDJSON) responses keep flushing
+// normally.
+type responseLogger struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+	bytesOut    int64
+	eventCount  int
+}
+
+func (rl *responseLogger) WriteHeader(code int) {
+	if rl.wroteHeader {
+		return
+	}
+	rl.wroteHeader = true
+	rl.code = code
+	rl.ResponseWriter.WriteHeader(code)
+}
+
+func (rl *responseLogger) Write(p []byte) (int, error) {
+	if !rl.wroteHeader {
+		rl.WriteHeader(http.StatusOK)
+	}
+
+	n, err := rl.ResponseWriter.Write(p)
+	rl.bytesOut += int64(n)
+	return n, err
+}
+
+// CountEvent records that one AG-UI event was written to the response.
+// writeSSEFrame and handleEncoded call this through the eventCounter
+// interface, since they're the only places that know "this write is one
+// event" -- scanning the written bytes for a marker like "data: " would
+// also match that text inside an event's own payload.
+func (rl *responseLogger) CountEvent() { rl.eventCount++ }
+
+func (rl *responseLogger) Flush() {
+	if f, ok := rl.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rl *responseLogger) status() int {
+	if rl.code == 0 {
+		return http.StatusOK
+	}
+	return rl.code
+}
+
+// loggerCtxKey is the request-context key JSONLoggerMiddleware uses to
+// thread its per-request Logger down to Handler.ServeHTTP.
+type loggerCtxKey struct{}
+
+func contextWithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+func loggerFromContext(ctx context.Context) Logger {
+	logger, _ := ctx.Value(loggerCtxKey{}).(Logger)
+	return logger
+}
+
+// WithFields returns a Logger that appends the given static key=value
+// fields, sorted by key, after every formatted message.
+func WithFields(base Logger, fields map[string]string) Logger {
+	return &fieldLogger{base: base, fields: fields}
+}
+
+type fieldLogger struct {
+	base   Logger
+	fields map[string]string
+}
+
+func (l *fieldLogger) Printf(format string, v ...any) {
+	l.base.Printf("%s %s", fmt.Sprintf(format, v...), formatFields(l.fields))
+}
+
+func formatFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, fields[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// redactingLogger applies redact to the rendered message before handing
+// it to base, so Printf's format/args aren't re-interpreted downstream.
+type redactingLogger struct {
+	base   Logger
+	redact func(string) string
+}
+
+func (l *redactingLogger) Printf(format string, v ...any) {
+	l.base.Printf("%s", l.redact(fmt.Sprintf(format, v...)))
+}
@@ -0,0 +1,75 @@
+package aguigo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type capturingLogger struct {
+	lines []string
+}
+
+func (l *capturingLogger) Printf(format string, v ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestWithFields(t *testing.T) {
+	captured := &capturingLogger{}
+	logger := WithFields(captured, map[string]string{"runId": "r1", "threadId": "t1"})
+
+	logger.Printf("hello %s", "world")
+
+	assert.Equal(t, []string{"hello world runId=r1 threadId=t1"}, captured.lines)
+}
+
+func TestJSONLoggerMiddleware(t *testing.T) {
+	captured := &capturingLogger{}
+	mockSource := &MockEventSource{
+		RunFunc: func(ctx HandlerContext, input RunAgentInput) <-chan events.Event {
+			assert.NotNil(t, ctx.Logger)
+			ch := make(chan events.Event, 1)
+			ch <- events.NewRunStartedEvent(input.ThreadID, input.RunID)
+			close(ch)
+			return ch
+		},
+	}
+	handler := New(Config{EventSource: mockSource})
+	middleware := JSONLoggerMiddleware(LoggerConfig{Logger: captured})(handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"threadId":"thread-1","runId":"run-1"}`))
+	req.Header.Set("Accept", "text/event-stream")
+	rr := httptest.NewRecorder()
+	middleware.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var entry accessLogEntry
+	assert.NoError(t, json.Unmarshal([]byte(captured.lines[len(captured.lines)-1]), &entry))
+	assert.Equal(t, "thread-1", entry.ThreadID)
+	assert.Equal(t, "run-1", entry.RunID)
+	assert.Equal(t, 1, entry.EventCount)
+}
+
+func TestJSONLoggerMiddleware_Sample(t *testing.T) {
+	captured := &capturingLogger{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := JSONLoggerMiddleware(LoggerConfig{
+		Logger: captured,
+		Sample: func(r *http.Request) bool { return false },
+	})(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	middleware.ServeHTTP(rr, req)
+
+	assert.Empty(t, captured.lines)
+}
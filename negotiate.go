@@ -0,0 +1,59 @@
+package aguigo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Media types recognized by Handler.selectEncoder and ContentTypeChecker.
+const (
+	mimeJSON   = "application/json"
+	mimeSSE    = "text/event-stream"
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// parseMediaType splits a single Accept/Content-Type entry into its media
+// type and quality value (defaulting to 1.0 when absent), ignoring any
+// other parameters such as charset.
+func parseMediaType(part string) (mediaType string, quality float64) {
+	fields := strings.Split(part, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(fields[0]))
+	quality = 1.0
+	for _, f := range fields[1:] {
+		f = strings.TrimSpace(f)
+		if v, ok := strings.CutPrefix(f, "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				quality = parsed
+			}
+		}
+	}
+	return mediaType, quality
+}
+
+// ContentTypeChecker returns middleware that rejects POST, PUT, and PATCH
+// requests whose Content-Type isn't application/json (an optional
+// charset parameter is allowed) with 415 Unsupported Media Type,
+// reported as an ErrorResponse.
+func ContentTypeChecker(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasRequestBody(r.Method) {
+			ct, _ := parseMediaType(r.Header.Get("Content-Type"))
+			if ct != mimeJSON {
+				JSONResponse(w, http.StatusUnsupportedMediaType, problemJSONContentType, ErrorResponse{
+					Title:    "Unsupported Media Type",
+					Status:   http.StatusUnsupportedMediaType,
+					Detail:   fmt.Sprintf("expected Content-Type %q", mimeJSON),
+					Instance: r.URL.Path,
+				})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hasRequestBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
@@ -0,0 +1,211 @@
+package aguigo
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+// Default sizing for the in-memory ReplayStore used when Config.ReplayStore
+// is nil: how many distinct runs to remember, and how many of each run's
+// most recent events to keep buffered for replay.
+const (
+	defaultReplayMaxRuns    = 256
+	defaultReplayBufferSize = 128
+)
+
+// ReplayStore buffers recently emitted events per run so a reconnecting
+// SSE client can replay what it missed using the Last-Event-ID header.
+// Implementations must be safe for concurrent use.
+type ReplayStore interface {
+	// Append records evt under runID with monotonically increasing id.
+	Append(runID string, id int64, evt events.Event)
+	// Since returns the events recorded after lastID for runID, in
+	// order. It returns nil if the run is unknown or has nothing newer.
+	Since(runID string, lastID int64) []events.Event
+}
+
+// NewMemoryReplayStore returns a ReplayStore that keeps, entirely in
+// process memory, the bufferSize most recent events for each of the
+// maxRuns most recently active runs, evicting the least recently
+// appended-to run once that limit is exceeded.
+func NewMemoryReplayStore(maxRuns, bufferSize int) ReplayStore {
+	return &memoryReplayStore{
+		maxRuns:    maxRuns,
+		bufferSize: bufferSize,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+type replayedEvent struct {
+	id  int64
+	evt events.Event
+}
+
+type runEntry struct {
+	runID  string
+	events []replayedEvent
+}
+
+func (e *runEntry) append(id int64, evt events.Event, limit int) {
+	e.events = append(e.events, replayedEvent{id, evt})
+	if len(e.events) > limit {
+		e.events = e.events[len(e.events)-limit:]
+	}
+}
+
+func (e *runEntry) since(lastID int64) []events.Event {
+	var out []events.Event
+	for _, re := range e.events {
+		if re.id > lastID {
+			out = append(out, re.evt)
+		}
+	}
+	return out
+}
+
+// memoryReplayStore is an LRU of runEntry, each holding a bounded tail of
+// that run's recent events.
+type memoryReplayStore struct {
+	mu         sync.Mutex
+	maxRuns    int
+	bufferSize int
+	order      *list.List // most recently active run at the front
+	entries    map[string]*list.Element
+}
+
+func (s *memoryReplayStore) Append(runID string, id int64, evt events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[runID]
+	if !ok {
+		el = s.order.PushFront(&runEntry{runID: runID})
+		s.entries[runID] = el
+		s.evictLocked()
+	} else {
+		s.order.MoveToFront(el)
+	}
+	el.Value.(*runEntry).append(id, evt, s.bufferSize)
+}
+
+func (s *memoryReplayStore) Since(runID string, lastID int64) []events.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[runID]
+	if !ok {
+		return nil
+	}
+	return el.Value.(*runEntry).since(lastID)
+}
+
+func (s *memoryReplayStore) evictLocked() {
+	for s.order.Len() > s.maxRuns {
+		back := s.order.Back()
+		if back == nil {
+			return
+		}
+		s.order.Remove(back)
+		delete(s.entries, back.Value.(*runEntry).runID)
+	}
+}
+
+// seqEvent pairs an event with the replay id it was published under, so
+// a live subscriber can keep writing accurate `id:` fields after having
+// caught up on the buffered backlog.
+type seqEvent struct {
+	id  int64
+	evt events.Event
+}
+
+// run tracks a single EventSource.Run invocation so it can outlive the
+// HTTP request that started it: the background goroutine started by
+// startRun keeps draining the EventSource and feeding the ReplayStore
+// even after the original client disconnects, and any SSE connection
+// (the original one, or a reconnect) can subscribe to the events still
+// to come.
+type run struct {
+	mu   sync.Mutex
+	subs map[chan seqEvent]struct{}
+	done chan struct{} // closed once the EventSource's channel is drained
+}
+
+func newRun() *run {
+	return &run{subs: make(map[chan seqEvent]struct{}), done: make(chan struct{})}
+}
+
+// subscribe registers a new listener for events published after this
+// call. The caller must invoke the returned unsubscribe func exactly
+// once when it stops reading.
+func (rn *run) subscribe() (ch chan seqEvent, unsubscribe func()) {
+	ch = make(chan seqEvent, 16)
+	rn.mu.Lock()
+	rn.subs[ch] = struct{}{}
+	rn.mu.Unlock()
+
+	return ch, func() {
+		rn.mu.Lock()
+		if _, ok := rn.subs[ch]; ok {
+			delete(rn.subs, ch)
+			close(ch)
+		}
+		rn.mu.Unlock()
+	}
+}
+
+func (rn *run) publish(se seqEvent) {
+	rn.mu.Lock()
+	defer rn.mu.Unlock()
+	for ch := range rn.subs {
+		select {
+		case ch <- se:
+		default:
+			// Slow subscriber: it can still catch up via ReplayStore on
+			// its next reconnect, so we don't block the run on it.
+		}
+	}
+}
+
+func (rn *run) markDone() {
+	close(rn.done)
+}
+
+// lookupRun returns the in-flight run registered for runID, if any.
+func (h *Handler) lookupRun(runID string) *run {
+	h.runsMu.Lock()
+	defer h.runsMu.Unlock()
+	return h.runs[runID]
+}
+
+// startRun registers a new run for hctx.RunID and starts a goroutine that
+// drains the EventSource independently of the calling request's
+// lifetime, assigning each event a monotonically increasing replay id
+// and feeding it to both the ReplayStore and any live subscribers.
+func (h *Handler) startRun(hctx HandlerContext, input RunAgentInput) *run {
+	rn := newRun()
+
+	h.runsMu.Lock()
+	h.runs[hctx.RunID] = rn
+	h.runsMu.Unlock()
+
+	src := h.eventSource.Run(hctx, input)
+
+	go func() {
+		var nextID int64
+		for evt := range src {
+			nextID++
+			h.replayStore.Append(hctx.RunID, nextID, evt)
+			rn.publish(seqEvent{id: nextID, evt: evt})
+		}
+		rn.markDone()
+
+		h.runsMu.Lock()
+		delete(h.runs, hctx.RunID)
+		h.runsMu.Unlock()
+	}()
+
+	return rn
+}
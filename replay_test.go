@@ -0,0 +1,76 @@
+package aguigo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryReplayStore(t *testing.T) {
+	t.Run("Since returns only events after lastID", func(t *testing.T) {
+		store := NewMemoryReplayStore(10, 10)
+		store.Append("run-1", 1, events.NewRunStartedEvent("t", "run-1"))
+		store.Append("run-1", 2, events.NewRunFinishedEvent("t", "run-1"))
+
+		assert.Len(t, store.Since("run-1", 0), 2)
+		assert.Len(t, store.Since("run-1", 1), 1)
+		assert.Len(t, store.Since("run-1", 2), 0)
+	})
+
+	t.Run("Unknown run returns nil", func(t *testing.T) {
+		store := NewMemoryReplayStore(10, 10)
+		assert.Nil(t, store.Since("missing", 0))
+	})
+
+	t.Run("bufferSize bounds events kept per run", func(t *testing.T) {
+		store := NewMemoryReplayStore(10, 2)
+		for i := int64(1); i <= 5; i++ {
+			store.Append("run-1", i, events.NewRunStartedEvent("t", "run-1"))
+		}
+		assert.Len(t, store.Since("run-1", 0), 2)
+	})
+
+	t.Run("maxRuns evicts the least recently appended-to run", func(t *testing.T) {
+		store := NewMemoryReplayStore(1, 10)
+		store.Append("run-1", 1, events.NewRunStartedEvent("t", "run-1"))
+		store.Append("run-2", 1, events.NewRunStartedEvent("t", "run-2"))
+
+		assert.Nil(t, store.Since("run-1", 0))
+		assert.Len(t, store.Since("run-2", 0), 1)
+	})
+}
+
+func TestHandler_SSEReconnect(t *testing.T) {
+	mockSource := &MockEventSource{
+		RunFunc: func(ctx HandlerContext, input RunAgentInput) <-chan events.Event {
+			ch := make(chan events.Event, 2)
+			ch <- events.NewRunStartedEvent("thread-1", "run-1")
+			ch <- events.NewRunFinishedEvent("thread-1", "run-1")
+			close(ch)
+			return ch
+		},
+	}
+	handler := New(Config{EventSource: mockSource})
+
+	// The initial POST streams the whole run: Handler.streamRun only
+	// returns once the background run's done channel closes, so by the
+	// time ServeHTTP returns here both events are guaranteed to already
+	// be in the ReplayStore.
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"threadId":"thread-1","runId":"run-1"}`))
+	req.Header.Set("Accept", "text/event-stream")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, 2, strings.Count(rr.Body.String(), "data:"))
+
+	// A reconnect with Last-Event-ID: 1 should only replay the second event.
+	reconnect := httptest.NewRequest(http.MethodGet, "/?runId=run-1&threadId=thread-1", nil)
+	reconnect.Header.Set("Last-Event-ID", "1")
+	rr2 := httptest.NewRecorder()
+	handler.ServeHTTP(rr2, reconnect)
+
+	assert.Equal(t, 1, strings.Count(rr2.Body.String(), "data:"))
+}
@@ -0,0 +1,151 @@
+package aguigo
+
+import (
+	"context"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// defaultShutdownTimeout is used when ServerConfig.ShutdownTimeout is zero.
+const defaultShutdownTimeout = 10 * time.Second
+
+// ServerConfig configures ListenAndServe.
+type ServerConfig struct {
+	// Addr is either a plain "host:port" (TCP) or a "unix:///path/to.sock"
+	// URI for a Unix domain socket.
+	Addr string
+
+	// Handler is served for every accepted connection, typically an
+	// *aguigo.Handler wrapped in CORSMiddleware/ContentTypeChecker/etc.
+	Handler http.Handler
+
+	// SocketMode sets the Unix socket's file permissions. It is ignored
+	// for TCP addresses; zero leaves the umask default in place.
+	SocketMode fs.FileMode
+	// SocketUID and SocketGID chown the Unix socket file. Zero leaves
+	// ownership unchanged. Ignored for TCP addresses.
+	SocketUID int
+	SocketGID int
+
+	// H2C enables cleartext HTTP/2, letting SSE streams multiplex over a
+	// single connection when fronted by an HTTP/2-aware proxy.
+	H2C bool
+
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight SSE
+	// streams to finish on their own before forcibly closing remaining
+	// connections. Defaults to defaultShutdownTimeout.
+	ShutdownTimeout time.Duration
+}
+
+// ListenAndServe binds cfg.Addr and serves cfg.Handler until ctx is
+// canceled (typically via signal.NotifyContext(ctx, os.Interrupt)). On
+// cancellation it gives in-flight requests up to cfg.ShutdownTimeout to
+// finish gracefully; since Handler's SSE loops select on the request
+// context, forcibly closing whatever connections remain after that
+// timeout is what actually drains them.
+func ListenAndServe(ctx context.Context, cfg ServerConfig) error {
+	shutdownTimeout := cfg.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	ln, err := listen(cfg)
+	if err != nil {
+		return err
+	}
+
+	handler := cfg.Handler
+	srv := &http.Server{Handler: handler}
+	if cfg.H2C {
+		srv.Handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ln) }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Shutdown only closes idle connections and waits for active ones;
+		// once its deadline passes, force the rest closed so their
+		// request contexts cancel and any still-streaming SSE loop exits.
+		return srv.Close()
+	}
+	return nil
+}
+
+// listen opens cfg.Addr, applying Unix socket permissions when relevant.
+func listen(cfg ServerConfig) (net.Listener, error) {
+	network, address := parseAddr(cfg.Addr)
+
+	if network == "unix" {
+		// Clear a stale socket file left behind by a previous, uncleanly
+		// terminated process; net.Listen fails with "address in use"
+		// otherwise.
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "unix" {
+		if err := applySocketPerms(address, cfg); err != nil {
+			ln.Close()
+			return nil, err
+		}
+	}
+
+	return ln, nil
+}
+
+func applySocketPerms(path string, cfg ServerConfig) error {
+	if cfg.SocketMode != 0 {
+		if err := os.Chmod(path, cfg.SocketMode); err != nil {
+			return err
+		}
+	}
+	if cfg.SocketUID != 0 || cfg.SocketGID != 0 {
+		// os.Chown leaves an id of -1 unchanged.
+		uid, gid := cfg.SocketUID, cfg.SocketGID
+		if uid == 0 {
+			uid = -1
+		}
+		if gid == 0 {
+			gid = -1
+		}
+		if err := os.Chown(path, uid, gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseAddr splits a "unix:///path" or "tcp://host:port" URI into the
+// network/address pair net.Listen expects, defaulting to tcp when no
+// scheme is present.
+func parseAddr(addr string) (network, address string) {
+	if rest, ok := strings.CutPrefix(addr, "unix://"); ok {
+		return "unix", rest
+	}
+	if rest, ok := strings.CutPrefix(addr, "tcp://"); ok {
+		return "tcp", rest
+	}
+	return "tcp", addr
+}
@@ -0,0 +1,52 @@
+package aguigo
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddr(t *testing.T) {
+	cases := []struct {
+		addr        string
+		wantNetwork string
+		wantAddress string
+	}{
+		{"unix:///tmp/aguigo.sock", "unix", "/tmp/aguigo.sock"},
+		{"tcp://127.0.0.1:8080", "tcp", "127.0.0.1:8080"},
+		{":8080", "tcp", ":8080"},
+	}
+
+	for _, tc := range cases {
+		network, address := parseAddr(tc.addr)
+		assert.Equal(t, tc.wantNetwork, network)
+		assert.Equal(t, tc.wantAddress, address)
+	}
+}
+
+func TestListenAndServe_UnixSocket(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "aguigo.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ListenAndServe(ctx, ServerConfig{
+			Addr:       "unix://" + sockPath,
+			Handler:    http.HandlerFunc(HealthHandler),
+			SocketMode: 0600,
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		info, err := os.Stat(sockPath)
+		return err == nil && info.Mode().Perm() == 0600
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errCh)
+}